@@ -0,0 +1,88 @@
+package logr
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// WithCaller returns a new log that injects "file", "line", and (if
+// resolvable) "func" fields identifying the call site of each subsequent
+// Info/Infof/Warn/Warnf/Debug/Debugf/Error/Errorf/Fatal*/Panic* call, as
+// well as calls to Info/Infof made on the *Info returned by V(). skip
+// additional stack frames are skipped above the immediate caller, as with
+// runtime.Caller, for callers that wrap these methods in their own helper.
+func (l *Log) WithCaller(skip int) *Log {
+	info := l.info
+	info.callerSkip = skip
+	info.captureCaller = true
+	return &Log{
+		info:          info,
+		err:           l.err,
+		callerSkip:    skip,
+		captureCaller: true,
+		stackDepth:    l.stackDepth,
+		captureStack:  l.captureStack,
+	}
+}
+
+// WithStack returns a new log that additionally attaches a "stack" field,
+// containing a captured stack trace up to depth frames deep, to subsequent
+// Error/Errorf calls. A depth of 0 uses a reasonable default.
+func (l *Log) WithStack(depth int) *Log {
+	return &Log{
+		info:          l.info,
+		err:           l.err,
+		callerSkip:    l.callerSkip,
+		captureCaller: l.captureCaller,
+		stackDepth:    depth,
+		captureStack:  true,
+	}
+}
+
+// effectiveInfo returns l's Info, augmented with extra fields if extra is
+// non-empty. It avoids the WithFields allocation when extra is empty, which
+// is the common case when caller capture is not enabled.
+func (l *Log) effectiveInfo(extra map[string]interface{}) *Info {
+	if len(extra) == 0 {
+		return &l.info
+	}
+	return l.info.WithFields(extra)
+}
+
+// callerFields returns the "file"/"line"/"func" fields for the caller
+// extraSkip frames above the caller of the exported logging method that
+// invoked callerFields directly (e.g. Log.Error or Info.Info), or nil if the
+// caller could not be resolved.
+func callerFields(extraSkip int) map[string]interface{} {
+	pc, file, line, ok := runtime.Caller(2 + extraSkip)
+	if !ok {
+		return nil
+	}
+	fields := map[string]interface{}{"file": file, "line": line}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		fields["func"] = fn.Name()
+	}
+	return fields
+}
+
+// captureStack renders a stack trace starting at the caller extraSkip
+// frames above the caller of the exported Log method that invoked
+// captureStack directly, up to depth frames deep (0 uses a default of 32).
+func captureStack(extraSkip, depth int) string {
+	if depth <= 0 {
+		depth = 32
+	}
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(3+extraSkip, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package logr
+
+import (
+	"strings"
+	"testing"
+)
+
+type capturingInfoLogger struct {
+	fields map[string]interface{}
+}
+
+func (c *capturingInfoLogger) LogInfo(level int, fields map[string]interface{}, msg string) {
+	c.fields = fields
+}
+
+func TestWithCallerSurvivesV(t *testing.T) {
+	backend := &capturingInfoLogger{}
+	l := New(backend, nil).WithCaller(0)
+
+	l.V(0).Info("hi")
+	if backend.fields["file"] == nil {
+		t.Fatal("V(0).Info() did not attach caller fields")
+	}
+	file, ok := backend.fields["file"].(string)
+	if !ok || !strings.HasSuffix(file, "caller_test.go") {
+		t.Errorf("file = %v, want suffix caller_test.go", backend.fields["file"])
+	}
+}
+
+func TestWithCallerDirectInfo(t *testing.T) {
+	backend := &capturingInfoLogger{}
+	l := New(backend, nil).WithCaller(0)
+
+	l.Info("hi")
+	file, ok := backend.fields["file"].(string)
+	if !ok || !strings.HasSuffix(file, "caller_test.go") {
+		t.Errorf("file = %v, want suffix caller_test.go", backend.fields["file"])
+	}
+}
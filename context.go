@@ -0,0 +1,77 @@
+package logr
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l, retrievable with FromContext.
+// It is typically called once by a server's request-handling middleware,
+// after attaching request-scoped fields to l with WithContext or WithFields.
+func NewContext(ctx context.Context, l *Log) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the *Log carried by ctx, or nil if ctx carries none.
+func FromContext(ctx context.Context) *Log {
+	l, _ := ctx.Value(contextKey{}).(*Log)
+	return l
+}
+
+// ContextExtractor pulls a well-known field, such as a trace ID, span ID,
+// or request ID, out of a context.Context. Extractors registered with
+// RegisterContextExtractor are run by Log.WithContext.
+type ContextExtractor func(ctx context.Context) (key string, value interface{}, ok bool)
+
+// contextExtractors holds the current []ContextExtractor slice. It is
+// replaced wholesale (rather than appended to in place) so that WithContext,
+// on the request hot path, can read it lock-free via atomic.Value; writers
+// serialize through contextExtractorsMu.
+var contextExtractors atomic.Value // holds []ContextExtractor
+
+var contextExtractorsMu sync.Mutex
+
+func init() {
+	contextExtractors.Store([]ContextExtractor(nil))
+}
+
+// RegisterContextExtractor registers an extractor to be run by every
+// subsequent call to Log.WithContext. It is safe to call concurrently,
+// including concurrently with WithContext, but is intended to be called
+// from an init function, for example by an HTTP or gRPC middleware package
+// that wants WithContext to automatically pick up a trace ID from the
+// incoming request's context.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	contextExtractorsMu.Lock()
+	defer contextExtractorsMu.Unlock()
+	old := contextExtractors.Load().([]ContextExtractor)
+	next := make([]ContextExtractor, len(old)+1)
+	copy(next, old)
+	next[len(old)] = extractor
+	contextExtractors.Store(next)
+}
+
+// WithContext returns a new log with fields extracted from ctx by every
+// extractor registered with RegisterContextExtractor. This lets an HTTP or
+// gRPC server attach a per-request logger with fields like a trace ID once,
+// and have downstream code retrieve it with FromContext after the server
+// stores it in ctx with NewContext.
+func (l *Log) WithContext(ctx context.Context) *Log {
+	extractors := contextExtractors.Load().([]ContextExtractor)
+	if len(extractors) == 0 {
+		return l
+	}
+	fields := make(map[string]interface{}, len(extractors))
+	for _, extract := range extractors {
+		if key, value, ok := extract(ctx); ok {
+			fields[key] = value
+		}
+	}
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
@@ -0,0 +1,38 @@
+package logr
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContextRoundTrip(t *testing.T) {
+	if got := FromContext(context.Background()); got != nil {
+		t.Fatalf("FromContext(empty) = %v, want nil", got)
+	}
+
+	l := New(&capturingLogger{}, nil)
+	ctx := NewContext(context.Background(), l)
+	if got := FromContext(ctx); got != l {
+		t.Errorf("FromContext(ctx) = %v, want %v", got, l)
+	}
+}
+
+type ctxKey string
+
+func TestWithContextAppliesRegisteredExtractors(t *testing.T) {
+	RegisterContextExtractor(func(ctx context.Context) (string, interface{}, bool) {
+		v, ok := ctx.Value(ctxKey("traceID")).(string)
+		return "traceID", v, ok
+	})
+
+	backend := &capturingLogger{}
+	l := New(backend, nil)
+
+	ctx := context.WithValue(context.Background(), ctxKey("traceID"), "abc123")
+	l = l.WithContext(ctx)
+	l.Info("request handled")
+
+	if got := backend.fields["traceID"]; got != "abc123" {
+		t.Errorf("traceID field = %v, want abc123", got)
+	}
+}
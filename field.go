@@ -0,0 +1,169 @@
+package logr
+
+import (
+	"fmt"
+	"time"
+)
+
+// FieldType identifies which of a Field's accessors holds its value.
+type FieldType int
+
+// The field types produced by the constructor functions in this package.
+const (
+	StringType FieldType = iota
+	IntType
+	FloatType
+	BoolType
+	TimeType
+	DurationType
+	ErrorType
+	StringerType
+	LazyType
+	rawFieldType // an already-boxed value, used when converting legacy map fields
+)
+
+// Field is a structured log field: a name paired with a typed value. Fields
+// are passed to backends implementing TypedInfoLogger/TypedErrorLogger as a
+// []Field, avoiding the map[string]interface{} boxing of the plain
+// InfoLogger/ErrorLogger interfaces; backends that only implement the plain
+// interfaces still receive the same data converted to a map.
+type Field struct {
+	Key   string
+	Type  FieldType
+	num   int64
+	flt   float64
+	str   string
+	iface interface{}
+}
+
+// String returns a Field with a string value.
+func String(key, value string) Field {
+	return Field{Key: key, Type: StringType, str: value}
+}
+
+// Int returns a Field with an int64 value.
+func Int(key string, value int64) Field {
+	return Field{Key: key, Type: IntType, num: value}
+}
+
+// Float returns a Field with a float64 value.
+func Float(key string, value float64) Field {
+	return Field{Key: key, Type: FloatType, flt: value}
+}
+
+// Bool returns a Field with a bool value.
+func Bool(key string, value bool) Field {
+	var n int64
+	if value {
+		n = 1
+	}
+	return Field{Key: key, Type: BoolType, num: n}
+}
+
+// Time returns a Field with a time.Time value.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Type: TimeType, iface: value}
+}
+
+// Duration returns a Field with a time.Duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: DurationType, num: int64(value)}
+}
+
+// Error returns a Field wrapping an error value.
+func Error(key string, err error) Field {
+	return Field{Key: key, Type: ErrorType, iface: err}
+}
+
+// Stringer returns a Field wrapping a fmt.Stringer, whose String method is
+// called only when the field is actually rendered.
+func Stringer(key string, value fmt.Stringer) Field {
+	return Field{Key: key, Type: StringerType, iface: value}
+}
+
+// Lazy returns a Field whose value is computed by calling fn only when the
+// field is actually rendered, for values that are expensive to produce.
+func Lazy(key string, fn func() interface{}) Field {
+	return Field{Key: key, Type: LazyType, iface: fn}
+}
+
+// Value returns the field's value as an interface{}, calling fn for Lazy
+// fields and String for Stringer fields. If the resolved value implements
+// Redactor, its Redacted() result is returned instead, matching the
+// substitution WithFields applies to the legacy map path.
+func (f Field) Value() interface{} {
+	v := f.rawValue()
+	if r, ok := v.(Redactor); ok {
+		return r.Redacted()
+	}
+	return v
+}
+
+// rawValue returns the field's value as an interface{} without applying
+// Redactor substitution.
+func (f Field) rawValue() interface{} {
+	switch f.Type {
+	case StringType:
+		return f.str
+	case IntType:
+		return f.num
+	case FloatType:
+		return f.flt
+	case BoolType:
+		return f.num != 0
+	case TimeType, ErrorType:
+		return f.iface
+	case DurationType:
+		return time.Duration(f.num)
+	case StringerType:
+		return f.iface.(fmt.Stringer).String()
+	case LazyType:
+		return f.iface.(func() interface{})()
+	case rawFieldType:
+		return f.iface
+	default:
+		return nil
+	}
+}
+
+// TypedInfoLogger is an optional extension to InfoLogger. A backend may
+// implement it to receive fields as a []Field directly, avoiding the
+// map[string]interface{} boxing of LogInfo on the hot path.
+type TypedInfoLogger interface {
+	LogInfoFields(level int, fields []Field, msg string)
+}
+
+// TypedErrorLogger is an optional extension to ErrorLogger. A backend may
+// implement it to receive fields as a []Field directly, avoiding the
+// map[string]interface{} boxing of LogError on the hot path.
+type TypedErrorLogger interface {
+	LogErrorFields(fields []Field, msg string)
+}
+
+// mapToFields converts legacy map-style fields to []Field, for backends
+// that implement TypedInfoLogger/TypedErrorLogger but were handed fields via
+// the older WithFields API. It returns nil if m is empty.
+func mapToFields(m map[string]interface{}) []Field {
+	if len(m) == 0 {
+		return nil
+	}
+	fields := make([]Field, 0, len(m))
+	for k, v := range m {
+		fields = append(fields, Field{Key: k, Type: rawFieldType, iface: v})
+	}
+	return fields
+}
+
+// fieldsToMap converts []Field to the legacy map[string]interface{} shape,
+// for backends that only implement InfoLogger/ErrorLogger. It returns nil if
+// fields is empty.
+func fieldsToMap(fields []Field) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		m[f.Key] = f.Value()
+	}
+	return m
+}
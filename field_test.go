@@ -0,0 +1,22 @@
+package logr
+
+import "testing"
+
+type redactedErr struct{ msg string }
+
+func (e redactedErr) Error() string          { return e.msg }
+func (e redactedErr) Redacted() interface{} { return "***" }
+
+func TestFieldValueRedacts(t *testing.T) {
+	f := Error("err", redactedErr{msg: "secret"})
+	if got := f.Value(); got != "***" {
+		t.Errorf("Value() = %v, want ***", got)
+	}
+}
+
+func TestFieldValueNoRedactorUnaffected(t *testing.T) {
+	f := String("name", "alice")
+	if got := f.Value(); got != "alice" {
+		t.Errorf("Value() = %v, want alice", got)
+	}
+}
@@ -11,7 +11,6 @@ package logr
 
 import "fmt"
 
-// TODO: consider structured logging, a la uber-go/zap
 // TODO: consider other bits of glog functionality like Flush, InfoDepth, OutputStats
 
 // InfoLogger represents the ability to log non-error messages.
@@ -26,38 +25,125 @@ type ErrorLogger interface {
 
 // Info logs non-error messages.
 type Info struct {
-	logger InfoLogger
-	level  int
-	fields map[string]interface{}
-	prefix string
+	logger      InfoLogger
+	level       int
+	fields      map[string]interface{}
+	typedFields []Field
+	prefix      string
+	enabled     bool
+
+	callerSkip    int
+	captureCaller bool
 }
 
 // NewInfo creates a new Info that logs to the given logger.
 func NewInfo(logger InfoLogger) *Info {
-	return &Info{logger: logger}
+	return &Info{logger: logger, enabled: true}
+}
+
+// Enabled reports whether this Info will actually log anything, taking into
+// account its verbosity level and any vmodule filter set with SetVModule.
+// Callers that build expensive arguments can guard on Enabled to avoid that
+// cost when the log statement is compiled out.
+func (l *Info) Enabled() bool {
+	return l.enabled
 }
 
 // Info calls LogInfo to its logger.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Info) Info(args ...interface{}) {
-	s := fmt.Sprintln(args...)
-	l.logger.LogInfo(l.level, l.fields, l.prefix+s[:len(s)-1])
+	if !l.enabled {
+		return
+	}
+	s := fmt.Sprintln(redactArgs(args)...)
+	msg := l.prefix + s[:len(s)-1]
+	if l.captureCaller {
+		if extra := callerFields(l.callerSkip); len(extra) > 0 {
+			l = l.mergeFields(extra)
+		}
+	}
+	l.emit(msg)
 }
 
 // Infof calls LogInfo to its logger.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Info) Infof(format string, args ...interface{}) {
-	l.logger.LogInfo(l.level, l.fields, l.prefix+fmt.Sprintf(format, args...))
+	if !l.enabled {
+		return
+	}
+	msg := l.prefix + fmt.Sprintf(format, redactArgs(args)...)
+	if l.captureCaller {
+		if extra := callerFields(l.callerSkip); len(extra) > 0 {
+			l = l.mergeFields(extra)
+		}
+	}
+	l.emit(msg)
+}
+
+// mergeFields returns a copy of l with extra merged into l.fields. Unlike
+// WithFields, it does not apply Redactor substitution, since it is used
+// internally for already-safe fields such as caller-site info.
+func (l *Info) mergeFields(extra map[string]interface{}) *Info {
+	f := make(map[string]interface{}, len(l.fields)+len(extra))
+	for k, v := range l.fields {
+		f[k] = v
+	}
+	for k, v := range extra {
+		f[k] = v
+	}
+	n := *l
+	n.fields = f
+	return &n
+}
+
+// emit dispatches msg to l.logger, preferring TypedInfoLogger (passing
+// fields as []Field, avoiding map boxing) when the backend implements it.
+func (l *Info) emit(msg string) {
+	if tl, ok := l.logger.(TypedInfoLogger); ok {
+		fields := append(mapToFields(l.fields), l.typedFields...)
+		tl.LogInfoFields(l.level, fields, msg)
+		return
+	}
+	l.logger.LogInfo(l.level, l.mapFields(), msg)
+}
+
+// mapFields returns l's fields and typedFields merged into the legacy
+// map[string]interface{} shape, for backends that don't implement
+// TypedInfoLogger/TypedErrorLogger.
+func (l *Info) mapFields() map[string]interface{} {
+	if len(l.typedFields) == 0 {
+		return l.fields
+	}
+	m := make(map[string]interface{}, len(l.fields)+len(l.typedFields))
+	for k, v := range l.fields {
+		m[k] = v
+	}
+	for _, f := range l.typedFields {
+		m[f.Key] = f.Value()
+	}
+	return m
 }
 
-// V returns a new log at the specific verbosity level.
-// A higher verbosity level means a log message is less important.
+// V returns a new log at the specific verbosity level. A higher verbosity
+// level means a log message is less important.
+//
+// The returned Info is disabled (Info/Infof become no-ops) unless level is at
+// or below the threshold for the calling source file: the level of the last
+// vmodule pattern set with SetVModule that matches the caller's file, or the
+// base verbosity set with SetVerbosity if no pattern matches. The threshold
+// is resolved once per callsite and cached; each call still looks up its
+// caller's program counter with runtime.Caller, then does a single map load
+// and integer compare.
 func (l *Info) V(level int) *Info {
 	return &Info{
-		logger: l.logger,
-		fields: l.fields,
-		level:  level,
-		prefix: l.prefix,
+		logger:        l.logger,
+		fields:        l.fields,
+		typedFields:   l.typedFields,
+		level:         level,
+		prefix:        l.prefix,
+		enabled:       vEnabled(level, 1),
+		callerSkip:    l.callerSkip,
+		captureCaller: l.captureCaller,
 	}
 }
 
@@ -71,20 +157,47 @@ func (l *Info) WithFields(fields map[string]interface{}) *Info {
 		f[k] = v
 	}
 	return &Info{
-		logger: l.logger,
-		fields: f,
-		level:  l.level,
-		prefix: l.prefix,
+		logger:        l.logger,
+		fields:        redactFields(f),
+		typedFields:   l.typedFields,
+		level:         l.level,
+		prefix:        l.prefix,
+		enabled:       l.enabled,
+		callerSkip:    l.callerSkip,
+		captureCaller: l.captureCaller,
+	}
+}
+
+// With returns a new log with the given structured fields appended. Unlike
+// WithFields, fields passed to backends implementing TypedInfoLogger are
+// kept as typed values rather than boxed into a map.
+func (l *Info) With(fields ...Field) *Info {
+	f := make([]Field, 0, len(l.typedFields)+len(fields))
+	f = append(f, l.typedFields...)
+	f = append(f, fields...)
+	return &Info{
+		logger:        l.logger,
+		fields:        l.fields,
+		typedFields:   f,
+		level:         l.level,
+		prefix:        l.prefix,
+		enabled:       l.enabled,
+		callerSkip:    l.callerSkip,
+		captureCaller: l.captureCaller,
 	}
 }
 
 // WithPrefix returns a new log that prefixes all messages with a given string.
 func (l *Info) WithPrefix(prefix string) *Info {
 	return &Info{
-		logger: l.logger,
-		fields: l.fields,
-		level:  l.level,
-		prefix: l.prefix + prefix,
+		logger:        l.logger,
+		fields:        l.fields,
+		typedFields:   l.typedFields,
+		level:         l.level,
+		prefix:        l.prefix + prefix,
+		enabled:       l.enabled,
+		callerSkip:    l.callerSkip,
+		captureCaller: l.captureCaller,
 	}
 }
 
@@ -92,11 +205,25 @@ func (l *Info) WithPrefix(prefix string) *Info {
 type Log struct {
 	info Info
 	err  ErrorLogger
+
+	callerSkip    int
+	captureCaller bool
+	stackDepth    int
+	captureStack  bool
 }
 
 // New creates a new log that sends output to the given loggers.
 func New(info InfoLogger, err ErrorLogger) *Log {
-	return &Log{info: Info{logger: info}, err: err}
+	return &Log{info: Info{logger: info, enabled: true}, err: err}
+}
+
+// NewSink creates a new log backed entirely by sink: Info/Error, as well as
+// Warn/Debug/Fatal/Panic, are all delivered through sink's single Log
+// method, with their true Severity preserved. Use this instead of New when
+// a backend implements only SinkLogger and has no separate
+// InfoLogger/ErrorLogger methods to pass.
+func NewSink(sink SinkLogger) *Log {
+	return &Log{info: Info{logger: sinkAsInfoLogger{sink}, enabled: true}, err: sinkAsErrorLogger{sink}}
 }
 
 // AsInfo returns the log as an info log.
@@ -107,40 +234,129 @@ func (l *Log) AsInfo() *Info {
 // Info calls LogInfo to its logger.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Log) Info(args ...interface{}) {
-	l.info.Info(args...)
+	if !l.info.enabled {
+		return
+	}
+	l.infoAtDepth(1).Info(args...)
 }
 
 // Infof calls LogInfo to its logger.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Log) Infof(format string, args ...interface{}) {
-	l.info.Infof(format, args...)
+	if !l.info.enabled {
+		return
+	}
+	l.infoAtDepth(1).Infof(format, args...)
+}
+
+// infoAtDepth returns l's Info, with callerSkip increased by extra frames
+// when WithCaller is active, to account for the extra stack frame(s) the
+// calling Log method (e.g. Log.Info) adds above Info.Info/Infof's own
+// caller-capturing logic. It avoids an allocation when caller capture is
+// not enabled.
+func (l *Log) infoAtDepth(extra int) *Info {
+	if !l.info.captureCaller {
+		return &l.info
+	}
+	n := l.info
+	n.callerSkip += extra
+	return &n
 }
 
-// Error calls LogError to its logger.
+// Error calls LogError to its logger. If WithStack was set, a "stack" field
+// is attached containing a captured stack trace.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Log) Error(args ...interface{}) {
-	s := fmt.Sprintln(args...)
-	l.err.LogError(l.info.fields, l.info.prefix+s[:len(s)-1])
+	var extra map[string]interface{}
+	if l.captureCaller || l.captureStack {
+		extra = make(map[string]interface{}, 4)
+		if l.captureCaller {
+			for k, v := range callerFields(l.callerSkip) {
+				extra[k] = v
+			}
+		}
+		if l.captureStack {
+			extra["stack"] = captureStack(l.callerSkip, l.stackDepth)
+		}
+	}
+	info := l.effectiveInfo(extra)
+	s := fmt.Sprintln(redactArgs(args)...)
+	l.emitError(info, info.prefix+s[:len(s)-1])
 }
 
-// Errorf calls LogError to its logger.
+// Errorf calls LogError to its logger. If WithStack was set, a "stack" field
+// is attached containing a captured stack trace.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Log) Errorf(format string, args ...interface{}) {
-	l.err.LogError(l.info.fields, l.info.prefix+fmt.Sprintf(format, args...))
+	var extra map[string]interface{}
+	if l.captureCaller || l.captureStack {
+		extra = make(map[string]interface{}, 4)
+		if l.captureCaller {
+			for k, v := range callerFields(l.callerSkip) {
+				extra[k] = v
+			}
+		}
+		if l.captureStack {
+			extra["stack"] = captureStack(l.callerSkip, l.stackDepth)
+		}
+	}
+	info := l.effectiveInfo(extra)
+	l.emitError(info, info.prefix+fmt.Sprintf(format, redactArgs(args)...))
+}
+
+// emitError dispatches msg to l.err, preferring TypedErrorLogger (passing
+// fields as []Field, avoiding map boxing) when the backend implements it.
+func (l *Log) emitError(info *Info, msg string) {
+	if tl, ok := l.err.(TypedErrorLogger); ok {
+		fields := append(mapToFields(info.fields), info.typedFields...)
+		tl.LogErrorFields(fields, msg)
+		return
+	}
+	l.err.LogError(info.mapFields(), msg)
 }
 
-// V returns a new log at the specific verbosity level.
-// A higher verbosity level means a log message is less important.
+// V returns a new log at the specific verbosity level. A higher verbosity
+// level means a log message is less important. See Info.V for how level is
+// gated by SetVerbosity and SetVModule.
 func (l *Log) V(level int) *Info {
-	return l.info.V(level)
+	return &Info{
+		logger:        l.info.logger,
+		fields:        l.info.fields,
+		typedFields:   l.info.typedFields,
+		level:         level,
+		prefix:        l.info.prefix,
+		enabled:       vEnabled(level, 1),
+		callerSkip:    l.info.callerSkip,
+		captureCaller: l.info.captureCaller,
+	}
 }
 
 // WithFields returns a new log with the given fields.
 func (l *Log) WithFields(fields map[string]interface{}) *Log {
-	return &Log{info: *l.info.WithFields(fields), err: l.err}
+	return l.derive(*l.info.WithFields(fields))
+}
+
+// With returns a new log with the given structured fields appended. See
+// Info.With for how fields are delivered to TypedInfoLogger/TypedErrorLogger
+// backends.
+func (l *Log) With(fields ...Field) *Log {
+	return l.derive(*l.info.With(fields...))
 }
 
 // WithPrefix returns a new log that prefixes all messages with a given string.
 func (l *Log) WithPrefix(prefix string) *Log {
-	return &Log{info: *l.info.WithPrefix(prefix), err: l.err}
+	return l.derive(*l.info.WithPrefix(prefix))
+}
+
+// derive returns a new Log with the given Info and l's other settings
+// (error backend, caller/stack capture configuration) carried over.
+func (l *Log) derive(info Info) *Log {
+	return &Log{
+		info:          info,
+		err:           l.err,
+		callerSkip:    l.callerSkip,
+		captureCaller: l.captureCaller,
+		stackDepth:    l.stackDepth,
+		captureStack:  l.captureStack,
+	}
 }
@@ -0,0 +1,69 @@
+package logr
+
+// Redactor is implemented by field and argument values that know how to
+// mask their own sensitive contents, such as passwords, tokens, or other
+// PII. WithFields and the Info/Error logging pipelines substitute the
+// Redacted() value for any value implementing this interface before it
+// reaches a backend InfoLogger or ErrorLogger, so backends never need to
+// know which values are sensitive.
+type Redactor interface {
+	Redacted() interface{}
+}
+
+// Redact returns a string of the same length as s with every byte replaced
+// by '*', so a multi-byte character becomes several '*'. It is a convenience for implementing Redactor.Redacted on
+// string secrets, matching the masking convention used by go-logging and
+// vitess.
+func Redact(s string) string {
+	b := make([]byte, len(s))
+	for i := range b {
+		b[i] = '*'
+	}
+	return string(b)
+}
+
+// redactFields returns fields with any Redactor values replaced by their
+// Redacted() result. It returns fields unchanged if nothing needed
+// redacting, to avoid an allocation on the common path.
+func redactFields(fields map[string]interface{}) map[string]interface{} {
+	var out map[string]interface{}
+	for k, v := range fields {
+		r, ok := v.(Redactor)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]interface{}, len(fields))
+			for k2, v2 := range fields {
+				out[k2] = v2
+			}
+		}
+		out[k] = r.Redacted()
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// redactArgs returns args with any Redactor values replaced by their
+// Redacted() result. It returns args unchanged if nothing needed redacting,
+// to avoid an allocation on the common path.
+func redactArgs(args []interface{}) []interface{} {
+	var out []interface{}
+	for i, a := range args {
+		r, ok := a.(Redactor)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]interface{}, len(args))
+			copy(out, args)
+		}
+		out[i] = r.Redacted()
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package logr
+
+import "testing"
+
+type secretValue struct{ value string }
+
+func (s secretValue) Redacted() interface{} { return Redact(s.value) }
+
+func TestRedactMasksSameLength(t *testing.T) {
+	got := Redact("hunter2")
+	if len(got) != len("hunter2") {
+		t.Fatalf("len(Redact(%q)) = %d, want %d", "hunter2", len(got), len("hunter2"))
+	}
+	for _, c := range got {
+		if c != '*' {
+			t.Fatalf("Redact(%q) = %q, want all asterisks", "hunter2", got)
+		}
+	}
+}
+
+type capturingLogger struct {
+	fields map[string]interface{}
+}
+
+func (c *capturingLogger) LogInfo(level int, fields map[string]interface{}, msg string) {
+	c.fields = fields
+}
+
+func TestWithFieldsRedactsSensitiveValues(t *testing.T) {
+	backend := &capturingLogger{}
+	l := NewInfo(backend)
+
+	l = l.WithFields(map[string]interface{}{
+		"password": secretValue{value: "hunter2"},
+		"user":     "alice",
+	})
+	l.Info("login")
+
+	if got := backend.fields["password"]; got != "*******" {
+		t.Errorf("password field = %v, want fully masked", got)
+	}
+	if got := backend.fields["user"]; got != "alice" {
+		t.Errorf("user field = %v, want unchanged", got)
+	}
+}
+
+func TestRedactArgsMasksRedactorValues(t *testing.T) {
+	got := redactArgs([]interface{}{"token:", secretValue{value: "topsecret"}})
+	if got[1] != "*********" {
+		t.Errorf("redacted arg = %v, want fully masked", got[1])
+	}
+}
@@ -0,0 +1,242 @@
+package logr
+
+import (
+	"fmt"
+	"os"
+)
+
+// Severity identifies the kind of message being logged, in ascending order
+// of importance.
+type Severity int
+
+// The severities a *Log can emit.
+const (
+	SeverityDebug Severity = iota
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+	SeverityFatal
+	SeverityPanic
+)
+
+// String returns the severity's name, e.g. "WARN".
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "DEBUG"
+	case SeverityInfo:
+		return "INFO"
+	case SeverityWarn:
+		return "WARN"
+	case SeverityError:
+		return "ERROR"
+	case SeverityFatal:
+		return "FATAL"
+	case SeverityPanic:
+		return "PANIC"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// SinkLogger is the unified backend contract: a single method that receives
+// every severity of message logged through a *Log. It supersedes the
+// InfoLogger/ErrorLogger pair passed to New, which are adapted to SinkLogger
+// automatically; a backend can instead implement SinkLogger directly (on
+// either value passed to New) to distinguish Warn/Debug/Fatal/Panic from
+// plain Info/Error.
+type SinkLogger interface {
+	Log(severity Severity, level int, fields map[string]interface{}, msg string)
+}
+
+// Flusher is implemented by backends that buffer output and need an
+// explicit flush. Log.Flush calls Flush on l's backends that implement it,
+// and Fatal/Panic call Flush before terminating so buffered output is not
+// lost.
+type Flusher interface {
+	Flush()
+}
+
+// sinkLoggerPair adapts the legacy InfoLogger+ErrorLogger pair to a
+// SinkLogger, routing Debug/Info through info and Warn/Error/Fatal/Panic
+// through err.
+type sinkLoggerPair struct {
+	info InfoLogger
+	err  ErrorLogger
+}
+
+func (s sinkLoggerPair) Log(severity Severity, level int, fields map[string]interface{}, msg string) {
+	if severity >= SeverityWarn {
+		if s.err != nil {
+			s.err.LogError(fields, msg)
+			return
+		}
+	}
+	if s.info != nil {
+		s.info.LogInfo(level, fields, msg)
+	}
+}
+
+// sinkAsInfoLogger adapts a SinkLogger to InfoLogger, for use as the info
+// backend of a *Log created by NewSink. It also implements SinkLogger and
+// Flusher itself, forwarding to the same underlying sink, so that Log.sink
+// and Log.Flush reach the one backend regardless of which field they probe.
+type sinkAsInfoLogger struct {
+	sink SinkLogger
+}
+
+func (s sinkAsInfoLogger) LogInfo(level int, fields map[string]interface{}, msg string) {
+	s.sink.Log(SeverityInfo, level, fields, msg)
+}
+
+func (s sinkAsInfoLogger) Log(severity Severity, level int, fields map[string]interface{}, msg string) {
+	s.sink.Log(severity, level, fields, msg)
+}
+
+func (s sinkAsInfoLogger) Flush() {
+	if f, ok := s.sink.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+// sinkAsErrorLogger adapts a SinkLogger to ErrorLogger, for use as the error
+// backend of a *Log created by NewSink. It also implements SinkLogger and
+// Flusher itself, forwarding to the same underlying sink, so that Log.sink
+// and Log.Flush reach the one backend regardless of which field they probe.
+type sinkAsErrorLogger struct {
+	sink SinkLogger
+}
+
+func (s sinkAsErrorLogger) LogError(fields map[string]interface{}, msg string) {
+	s.sink.Log(SeverityError, 0, fields, msg)
+}
+
+func (s sinkAsErrorLogger) Log(severity Severity, level int, fields map[string]interface{}, msg string) {
+	s.sink.Log(severity, level, fields, msg)
+}
+
+func (s sinkAsErrorLogger) Flush() {
+	if f, ok := s.sink.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+// sink returns the SinkLogger that Warn/Debug/Fatal/Panic dispatch through,
+// preferring a backend that implements SinkLogger directly.
+func (l *Log) sink() SinkLogger {
+	if sl, ok := l.err.(SinkLogger); ok {
+		return sl
+	}
+	if sl, ok := l.info.logger.(SinkLogger); ok {
+		return sl
+	}
+	return sinkLoggerPair{info: l.info.logger, err: l.err}
+}
+
+func (l *Log) logSeverity(info *Info, severity Severity, msg string) {
+	l.sink().Log(severity, info.level, info.mapFields(), msg)
+}
+
+// Flush flushes l's backends that implement Flusher.
+func (l *Log) Flush() {
+	if f, ok := l.info.logger.(Flusher); ok {
+		f.Flush()
+	}
+	if f, ok := l.err.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Warn logs a message more severe than Info but less severe than Error.
+// Arguments are handled in the manner of fmt.Println.
+func (l *Log) Warn(args ...interface{}) {
+	var extra map[string]interface{}
+	if l.captureCaller {
+		extra = callerFields(l.callerSkip)
+	}
+	s := fmt.Sprintln(redactArgs(args)...)
+	l.logSeverity(l.effectiveInfo(extra), SeverityWarn, l.info.prefix+s[:len(s)-1])
+}
+
+// Warnf logs a message more severe than Info but less severe than Error.
+// Arguments are handled in the manner of fmt.Printf.
+func (l *Log) Warnf(format string, args ...interface{}) {
+	var extra map[string]interface{}
+	if l.captureCaller {
+		extra = callerFields(l.callerSkip)
+	}
+	l.logSeverity(l.effectiveInfo(extra), SeverityWarn, l.info.prefix+fmt.Sprintf(format, redactArgs(args)...))
+}
+
+// Debug logs a message less severe than Info.
+// Arguments are handled in the manner of fmt.Println.
+func (l *Log) Debug(args ...interface{}) {
+	var extra map[string]interface{}
+	if l.captureCaller {
+		extra = callerFields(l.callerSkip)
+	}
+	s := fmt.Sprintln(redactArgs(args)...)
+	l.logSeverity(l.effectiveInfo(extra), SeverityDebug, l.info.prefix+s[:len(s)-1])
+}
+
+// Debugf logs a message less severe than Info.
+// Arguments are handled in the manner of fmt.Printf.
+func (l *Log) Debugf(format string, args ...interface{}) {
+	var extra map[string]interface{}
+	if l.captureCaller {
+		extra = callerFields(l.callerSkip)
+	}
+	l.logSeverity(l.effectiveInfo(extra), SeverityDebug, l.info.prefix+fmt.Sprintf(format, redactArgs(args)...))
+}
+
+// Fatal logs a message, flushes l's backends, then calls os.Exit(1).
+// Arguments are handled in the manner of fmt.Println.
+func (l *Log) Fatal(args ...interface{}) {
+	var extra map[string]interface{}
+	if l.captureCaller {
+		extra = callerFields(l.callerSkip)
+	}
+	s := fmt.Sprintln(redactArgs(args)...)
+	l.logSeverity(l.effectiveInfo(extra), SeverityFatal, l.info.prefix+s[:len(s)-1])
+	l.Flush()
+	os.Exit(1)
+}
+
+// Fatalf logs a message, flushes l's backends, then calls os.Exit(1).
+// Arguments are handled in the manner of fmt.Printf.
+func (l *Log) Fatalf(format string, args ...interface{}) {
+	var extra map[string]interface{}
+	if l.captureCaller {
+		extra = callerFields(l.callerSkip)
+	}
+	l.logSeverity(l.effectiveInfo(extra), SeverityFatal, l.info.prefix+fmt.Sprintf(format, redactArgs(args)...))
+	l.Flush()
+	os.Exit(1)
+}
+
+// Panic logs a message, flushes l's backends, then panics with the message.
+// Arguments are handled in the manner of fmt.Println.
+func (l *Log) Panic(args ...interface{}) {
+	var extra map[string]interface{}
+	if l.captureCaller {
+		extra = callerFields(l.callerSkip)
+	}
+	s := fmt.Sprintln(redactArgs(args)...)
+	msg := l.info.prefix + s[:len(s)-1]
+	l.logSeverity(l.effectiveInfo(extra), SeverityPanic, msg)
+	l.Flush()
+	panic(msg)
+}
+
+// Panicf logs a message, flushes l's backends, then panics with the message.
+// Arguments are handled in the manner of fmt.Printf.
+func (l *Log) Panicf(format string, args ...interface{}) {
+	var extra map[string]interface{}
+	if l.captureCaller {
+		extra = callerFields(l.callerSkip)
+	}
+	msg := l.info.prefix + fmt.Sprintf(format, redactArgs(args)...)
+	l.logSeverity(l.effectiveInfo(extra), SeverityPanic, msg)
+	l.Flush()
+	panic(msg)
+}
@@ -0,0 +1,61 @@
+package logr
+
+import (
+	"strings"
+	"testing"
+)
+
+type flushTrackingLogger struct {
+	capturingLogger
+	errFields map[string]interface{}
+	errMsg    string
+	logged    bool
+	flushed   bool
+}
+
+func (f *flushTrackingLogger) LogError(fields map[string]interface{}, msg string) {
+	f.errFields = fields
+	f.errMsg = msg
+	f.logged = true
+}
+
+func (f *flushTrackingLogger) Flush() {
+	f.flushed = true
+}
+
+func TestWithStackAttachesStackFieldOnError(t *testing.T) {
+	backend := &flushTrackingLogger{}
+	l := New(backend, backend).WithStack(0)
+
+	l.Error("boom")
+
+	stack, ok := backend.errFields["stack"].(string)
+	if !ok || stack == "" {
+		t.Fatalf("errFields[\"stack\"] = %v, want a non-empty string", backend.errFields["stack"])
+	}
+	if !strings.Contains(stack, "TestWithStackAttachesStackFieldOnError") {
+		t.Errorf("stack = %q, want it to mention the calling test", stack)
+	}
+}
+
+func TestPanicLogsFlushesThenPanics(t *testing.T) {
+	backend := &flushTrackingLogger{}
+	l := New(backend, backend)
+
+	defer func() {
+		r := recover()
+		if r != "boom" {
+			t.Fatalf("recovered %v, want \"boom\"", r)
+		}
+		if backend.errFields["stack"] != nil {
+			t.Error("Panic without WithStack should not attach a stack field")
+		}
+		if !backend.logged || backend.errMsg != "boom" {
+			t.Error("Panic should have logged \"boom\" through LogError before panicking")
+		}
+		if !backend.flushed {
+			t.Error("Panic should flush before panicking")
+		}
+	}()
+	l.Panic("boom")
+}
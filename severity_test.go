@@ -0,0 +1,30 @@
+package logr
+
+import "testing"
+
+type recordingSink struct {
+	records []Record
+}
+
+func (s *recordingSink) Log(severity Severity, level int, fields map[string]interface{}, msg string) {
+	s.records = append(s.records, Record{Severity: severity, Level: level, Fields: fields, Message: msg})
+}
+
+func TestNewSinkRoutesInfoAndErrorThroughSink(t *testing.T) {
+	sink := &recordingSink{}
+	l := NewSink(sink)
+
+	l.Info("hello")
+	l.Error("oops")
+	l.Warn("careful")
+
+	if len(sink.records) != 3 {
+		t.Fatalf("got %d records, want 3", len(sink.records))
+	}
+	want := []Severity{SeverityInfo, SeverityError, SeverityWarn}
+	for i, sev := range want {
+		if sink.records[i].Severity != sev {
+			t.Errorf("record %d severity = %v, want %v", i, sink.records[i].Severity, sev)
+		}
+	}
+}
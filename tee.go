@@ -0,0 +1,130 @@
+package logr
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Record is a single log entry passed to a RecordSink. It carries a
+// monotonically increasing Sequence and a Time, so sinks fed by multiple
+// goroutines can deduplicate or order records after the fact.
+type Record struct {
+	Sequence uint64
+	Time     time.Time
+	Severity Severity
+	Level    int
+	Fields   map[string]interface{}
+	Message  string
+}
+
+// RecordSink is a backend that receives a fully-populated Record. Tee and
+// TeeError fan a record out to every RecordSink whose SinkConfig accepts it.
+type RecordSink interface {
+	LogRecord(r Record)
+}
+
+// recordSinkAdapter adapts the legacy InfoLogger/ErrorLogger interfaces to
+// RecordSink, so existing backends can be used as Tee sinks unchanged.
+type recordSinkAdapter struct {
+	info InfoLogger
+	err  ErrorLogger
+}
+
+// RecordSinkFromInfoLogger adapts an InfoLogger to a RecordSink, mapping a
+// Record back to a LogInfo call.
+func RecordSinkFromInfoLogger(logger InfoLogger) RecordSink {
+	return recordSinkAdapter{info: logger}
+}
+
+// RecordSinkFromErrorLogger adapts an ErrorLogger to a RecordSink, mapping a
+// Record of SeverityError or above back to a LogError call. Records less
+// severe than SeverityError are not passed to logger.
+func RecordSinkFromErrorLogger(logger ErrorLogger) RecordSink {
+	return recordSinkAdapter{err: logger}
+}
+
+func (a recordSinkAdapter) LogRecord(r Record) {
+	if a.err != nil && r.Severity >= SeverityError {
+		a.err.LogError(r.Fields, r.Message)
+		return
+	}
+	if a.info != nil {
+		a.info.LogInfo(r.Level, r.Fields, r.Message)
+	}
+}
+
+// SinkConfig configures one sink registered with Tee or TeeError: the
+// verbosity it wants and an optional predicate over a record's fields.
+type SinkConfig struct {
+	// Sink receives every Record accepted by MinVerbosity and Match.
+	Sink RecordSink
+	// MinVerbosity is the highest V level Sink wants to see; a record at a
+	// higher (less important) level is dropped for this sink.
+	MinVerbosity int
+	// Match, if non-nil, is an additional predicate over the record's
+	// fields; a record is dropped for this sink unless Match returns true.
+	Match func(fields map[string]interface{}) bool
+}
+
+func (sc SinkConfig) accepts(r Record) bool {
+	if r.Severity < SeverityError && r.Level > sc.MinVerbosity {
+		return false
+	}
+	if sc.Match != nil && !sc.Match(r.Fields) {
+		return false
+	}
+	return true
+}
+
+var teeSequence uint64
+
+func nextSequence() uint64 {
+	return atomic.AddUint64(&teeSequence, 1)
+}
+
+// teeLogger fans each record out to every accepting sink.
+type teeLogger []SinkConfig
+
+func (t teeLogger) dispatch(r Record) {
+	r.Sequence = nextSequence()
+	r.Time = time.Now()
+	for _, sc := range t {
+		if sc.Sink == nil || !sc.accepts(r) {
+			continue
+		}
+		sc.Sink.LogRecord(r)
+	}
+}
+
+func (t teeLogger) LogInfo(level int, fields map[string]interface{}, msg string) {
+	t.dispatch(Record{Severity: SeverityInfo, Level: level, Fields: fields, Message: msg})
+}
+
+func (t teeLogger) LogError(fields map[string]interface{}, msg string) {
+	t.dispatch(Record{Severity: SeverityError, Fields: fields, Message: msg})
+}
+
+// Log implements SinkLogger, so a *Log built with Tee/TeeError as its
+// info/error backend routes Warn/Debug/Fatal/Panic here too, with their
+// true severity preserved in the Record (rather than being collapsed to
+// SeverityInfo/SeverityError by sinkLoggerPair), so SinkConfig.MinVerbosity
+// filtering applies correctly to every severity.
+func (t teeLogger) Log(severity Severity, level int, fields map[string]interface{}, msg string) {
+	t.dispatch(Record{Severity: severity, Level: level, Fields: fields, Message: msg})
+}
+
+// Tee returns an InfoLogger that forwards each record to every sink in
+// sinks whose MinVerbosity and Match accept it. This mirrors go-logging's
+// multiple-backends-with-different-levels model, letting a program emit
+// JSON to a file at V=0 and a colored console at V=2 without duplicating
+// call sites.
+func Tee(sinks ...SinkConfig) InfoLogger {
+	return teeLogger(sinks)
+}
+
+// TeeError returns an ErrorLogger that forwards each record to every sink
+// in sinks whose Match accepts it. MinVerbosity does not restrict errors,
+// since Log.Error does not carry a V level.
+func TeeError(sinks ...SinkConfig) ErrorLogger {
+	return teeLogger(sinks)
+}
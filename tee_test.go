@@ -0,0 +1,45 @@
+package logr
+
+import "testing"
+
+type collectingRecordSink struct {
+	records []Record
+}
+
+func (c *collectingRecordSink) LogRecord(r Record) {
+	c.records = append(c.records, r)
+}
+
+func TestTeePreservesSeverityForWarn(t *testing.T) {
+	sink := &collectingRecordSink{}
+	l := New(Tee(SinkConfig{Sink: sink, MinVerbosity: 0}), TeeError(SinkConfig{Sink: sink, MinVerbosity: 0}))
+
+	l.Warn("be careful")
+
+	if len(sink.records) != 1 {
+		t.Fatalf("got %d records, want 1", len(sink.records))
+	}
+	if sink.records[0].Severity != SeverityWarn {
+		t.Errorf("severity = %v, want SeverityWarn", sink.records[0].Severity)
+	}
+}
+
+func TestTeeSinkConfigFiltersInfoByVerbosityNotWarn(t *testing.T) {
+	// Enable V(2) globally, so that it is the sink's MinVerbosity, not V's
+	// own gate, that drops the V(2) record.
+	defer SetVerbosity(0)
+	SetVerbosity(2)
+
+	sink := &collectingRecordSink{}
+	l := New(Tee(SinkConfig{Sink: sink, MinVerbosity: 0}), TeeError(SinkConfig{Sink: sink, MinVerbosity: 0}))
+
+	l.V(2).Info("noisy")
+	l.Warn("should still pass, Warn has no V level")
+
+	if len(sink.records) != 1 {
+		t.Fatalf("got %d records, want 1 (the Warn)", len(sink.records))
+	}
+	if sink.records[0].Severity != SeverityWarn {
+		t.Errorf("severity = %v, want SeverityWarn", sink.records[0].Severity)
+	}
+}
@@ -0,0 +1,190 @@
+package logr
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// baseVerbosity is the default verbosity threshold used when no vmodule
+// pattern matches the calling file. It is analogous to glog/klog's -v flag.
+var baseVerbosity int32
+
+// SetVerbosity sets the default verbosity threshold used by V when no
+// vmodule pattern matches the calling file.
+func SetVerbosity(level int) {
+	atomic.StoreInt32(&baseVerbosity, int32(level))
+	clearVCache()
+}
+
+type vmodulePattern struct {
+	pattern string
+	level   int32
+}
+
+// vmoduleConfig holds the parsed state of the current vmodule spec. It is
+// replaced wholesale (rather than mutated) so it can be read lock-free via
+// an atomic.Value.
+type vmoduleConfig struct {
+	spec     string
+	patterns []vmodulePattern
+}
+
+var currentVModule atomic.Value // holds *vmoduleConfig
+
+// vcache maps a callsite's program counter to the verbosity threshold that
+// applies there, so repeated V() calls from the same line only pay for a
+// runtime.Caller lookup of their program counter, a single load and an
+// integer compare.
+var vcache sync.Map // map[uintptr]int32
+
+func init() {
+	currentVModule.Store(&vmoduleConfig{})
+}
+
+// SetVModule sets a glog/klog-style vmodule filter, overriding the default
+// verbosity threshold on a per-file basis. spec is a comma-separated list of
+// pattern=level pairs, for example "server=2,cache/*=3", matching files
+// "server.go" and any file directly inside a "cache" directory,
+// respectively. Patterns are matched against the trailing path components
+// of the calling source file, with its ".go" suffix stripped, using
+// path.Match-style globbing.
+func SetVModule(spec string) error {
+	cfg, err := parseVModule(spec)
+	if err != nil {
+		return err
+	}
+	currentVModule.Store(cfg)
+	clearVCache()
+	return nil
+}
+
+// clearVCache discards every cached per-callsite verbosity threshold, so
+// that subsequent V() calls are re-resolved against the current
+// baseVerbosity/vmodule configuration. It is called whenever that
+// configuration changes.
+func clearVCache() {
+	vcache.Range(func(k, _ interface{}) bool {
+		vcache.Delete(k)
+		return true
+	})
+}
+
+func parseVModule(spec string) (*vmoduleConfig, error) {
+	cfg := &vmoduleConfig{spec: spec}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.LastIndex(entry, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("logr: invalid vmodule entry %q: missing \"=level\"", entry)
+		}
+		pattern, levelStr := entry[:i], entry[i+1:]
+		if pattern == "" {
+			return nil, fmt.Errorf("logr: invalid vmodule entry %q: empty pattern", entry)
+		}
+		level, err := strconv.Atoi(levelStr)
+		if err != nil {
+			return nil, fmt.Errorf("logr: invalid vmodule entry %q: %v", entry, err)
+		}
+		for _, comp := range strings.Split(pattern, "/") {
+			if _, err := path.Match(comp, ""); err != nil {
+				return nil, fmt.Errorf("logr: invalid vmodule pattern %q: %v", pattern, err)
+			}
+		}
+		cfg.patterns = append(cfg.patterns, vmodulePattern{pattern: pattern, level: int32(level)})
+	}
+	return cfg, nil
+}
+
+// vmoduleFlag adapts SetVModule to the flag.Value interface, in the style of
+// glog's -vmodule flag.
+type vmoduleFlag struct{}
+
+// VModuleFlag returns a flag.Value that parses and applies a vmodule spec
+// via SetVModule. Typical usage:
+//
+//	flag.Var(logr.VModuleFlag(), "vmodule", "comma-separated list of pattern=N settings for file-filtered verbosity")
+func VModuleFlag() flag.Value {
+	return vmoduleFlag{}
+}
+
+func (vmoduleFlag) String() string {
+	cfg, _ := currentVModule.Load().(*vmoduleConfig)
+	if cfg == nil {
+		return ""
+	}
+	return cfg.spec
+}
+
+func (vmoduleFlag) Set(spec string) error {
+	return SetVModule(spec)
+}
+
+// vEnabled reports whether level is enabled for the caller skip frames above
+// its own caller (skip follows the runtime.Caller convention: 0 means
+// vEnabled's own caller).
+func vEnabled(level int, skip int) bool {
+	if level <= 0 {
+		return true
+	}
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return int32(level) <= atomic.LoadInt32(&baseVerbosity)
+	}
+	if v, ok := vcache.Load(pc); ok {
+		return int32(level) <= v.(int32)
+	}
+	threshold := thresholdForPC(pc)
+	vcache.Store(pc, threshold)
+	return int32(level) <= threshold
+}
+
+func thresholdForPC(pc uintptr) int32 {
+	cfg := currentVModule.Load().(*vmoduleConfig)
+	if len(cfg.patterns) == 0 {
+		return atomic.LoadInt32(&baseVerbosity)
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return atomic.LoadInt32(&baseVerbosity)
+	}
+	file, _ := fn.FileLine(pc)
+	threshold := atomic.LoadInt32(&baseVerbosity)
+	for _, p := range cfg.patterns {
+		if matchVModulePattern(p.pattern, file) {
+			threshold = p.level
+		}
+	}
+	return threshold
+}
+
+// matchVModulePattern reports whether the trailing components of file match
+// pattern, component by component, where file is a slash-separated or
+// OS-separated source path such as "/home/user/proj/cache/lru.go". As with
+// glog/klog's -vmodule, the final component's ".go" suffix is stripped
+// before matching, so a pattern of "server" matches a file "server.go".
+func matchVModulePattern(pattern, file string) bool {
+	file = strings.ReplaceAll(file, "\\", "/")
+	file = strings.TrimSuffix(file, ".go")
+	patternParts := strings.Split(pattern, "/")
+	fileParts := strings.Split(file, "/")
+	if len(patternParts) > len(fileParts) {
+		return false
+	}
+	fileParts = fileParts[len(fileParts)-len(patternParts):]
+	for i, p := range patternParts {
+		matched, err := path.Match(p, fileParts[i])
+		if err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package logr
+
+import "testing"
+
+func TestSetVerbosityInvalidatesCache(t *testing.T) {
+	defer SetVerbosity(0)
+
+	// Both checks go through the same callsite, so the second one would see
+	// the first one's cached threshold unless SetVerbosity cleared it.
+	for _, verbosity := range []int{0, 5} {
+		SetVerbosity(verbosity)
+		enabled := vEnabled(2, 0)
+		if verbosity == 0 && enabled {
+			t.Fatal("V(2) should be disabled at verbosity 0")
+		}
+		if verbosity == 5 && !enabled {
+			t.Fatal("V(2) should be enabled at verbosity 5 after SetVerbosity invalidates the cache")
+		}
+	}
+}
+
+func TestMatchVModulePatternStripsGoSuffix(t *testing.T) {
+	if !matchVModulePattern("server", "/home/user/proj/server.go") {
+		t.Error(`matchVModulePattern("server", ".../server.go") = false, want true`)
+	}
+	if !matchVModulePattern("cache/*", "/home/user/proj/cache/lru.go") {
+		t.Error(`matchVModulePattern("cache/*", ".../cache/lru.go") = false, want true`)
+	}
+	if matchVModulePattern("server", "/home/user/proj/other.go") {
+		t.Error(`matchVModulePattern("server", ".../other.go") = true, want false`)
+	}
+}
+
+func TestSetVModuleGatesVByFile(t *testing.T) {
+	defer SetVModule("")
+	defer SetVerbosity(0)
+
+	SetVerbosity(0)
+	if err := SetVModule("vmodule_test=3"); err != nil {
+		t.Fatalf("SetVModule: %v", err)
+	}
+	if !vEnabled(3, 0) {
+		t.Error("V(3) should be enabled in vmodule_test.go per the vmodule_test=3 pattern")
+	}
+	if vEnabled(4, 0) {
+		t.Error("V(4) should be disabled above the vmodule_test=3 threshold")
+	}
+}